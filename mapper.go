@@ -4,19 +4,78 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 const mapperConnector = "--"
+const defaultTagName = "mapper"
 
 type MapperFunc func(any) (any, error)
 
+// MappingErrorReason classifies why a Map/MapE call failed.
+type MappingErrorReason int
+
+const (
+	ErrNotPointer MappingErrorReason = iota
+	ErrMissingField
+	ErrIncompatibleTypes
+	ErrConverterFailed
+	ErrWrapperTooManyFields
+)
+
+func (r MappingErrorReason) String() string {
+	switch r {
+	case ErrNotPointer:
+		return "dest must be a pointer type"
+	case ErrMissingField:
+		return "missing field"
+	case ErrIncompatibleTypes:
+		return "incompatible types"
+	case ErrConverterFailed:
+		return "converter failed"
+	case ErrWrapperTooManyFields:
+		return "wrapper type has too many fields"
+	default:
+		return "unknown mapping error"
+	}
+}
+
+// MappingError describes a single failed field/value mapping, including the
+// dotted path (as accumulated while recursing) to the offending source and
+// dest values.
+type MappingError struct {
+	SourcePath string
+	DestPath   string
+	SourceType reflect.Type
+	DestType   reflect.Type
+	Reason     MappingErrorReason
+	Err        error
+}
+
+func (e *MappingError) Error() string {
+	msg := fmt.Sprintf("go_mapper: %s: dest %q (%v) <- source %q (%v)", e.Reason, e.DestPath, e.DestType, e.SourcePath, e.SourceType)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *MappingError) Unwrap() error {
+	return e.Err
+}
+
 type IMapper interface {
 	Map(source, dest interface{}, loose bool)
+	MapE(source, dest interface{}, loose bool) error
+	MapCollect(source, dest interface{}, loose bool) []*MappingError
 
 	RegisterMapping(from, to string, f MapperFunc)
 	UnregisterMapping(from, to string)
 	SetWrapperType(typeStr string, wrapper bool)
+	PrecompileMapping(source, dest interface{})
+	SetTagName(name string)
 }
 
 var mapper IMapper
@@ -33,6 +92,17 @@ func Map(source, dest interface{}, loose bool) {
 	mapper.Map(source, dest, loose)
 }
 
+// MapE behaves like Map but returns a *MappingError instead of panicking.
+func MapE(source, dest interface{}, loose bool) error {
+	return mapper.MapE(source, dest, loose)
+}
+
+// MapCollect behaves like MapE but keeps going after a field-level failure,
+// returning every failure it encountered instead of stopping at the first.
+func MapCollect(source, dest interface{}, loose bool) []*MappingError {
+	return mapper.MapCollect(source, dest, loose)
+}
+
 func RegisterMapping(from, to string, f MapperFunc) {
 	mapper.RegisterMapping(from, to, f)
 }
@@ -45,25 +115,64 @@ func SetWrapperType(typeStr string, wrapper bool) {
 	mapper.SetWrapperType(typeStr, wrapper)
 }
 
+// PrecompileMapping warms the struct field plan cache for source -> dest so
+// the first real Map call for this type pair doesn't pay the resolution cost.
+func PrecompileMapping(source, dest interface{}) {
+	mapper.PrecompileMapping(source, dest)
+}
+
+// SetTagName changes the struct tag key used for field-mapping directives
+// (default "mapper"). See mapperFieldTag for the supported directives.
+func SetTagName(name string) {
+	mapper.SetTagName(name)
+}
+
 type defaultMapper struct {
-	valueMap   map[string]MapperFunc
-	wrapperMap map[string]bool
-	lock       sync.RWMutex
+	generation        uint64
+	valueMap          map[string]MapperFunc
+	wrapperMap        map[string]bool
+	lock              sync.RWMutex
+	plans             sync.Map
+	visibleFieldCache sync.Map
+	tagName           string
 }
 
 func NewDefaultMapper() IMapper {
 	return &defaultMapper{
+		0,
 		make(map[string]MapperFunc),
 		make(map[string]bool),
 		sync.RWMutex{},
+		sync.Map{},
+		sync.Map{},
+		defaultTagName,
 	}
 }
 
+func (d *defaultMapper) SetTagName(name string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if name == "" {
+		name = defaultTagName
+	}
+	d.tagName = name
+	atomic.AddUint64(&d.generation, 1)
+}
+
+func (d *defaultMapper) getTagName() string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	return d.tagName
+}
+
 func (d *defaultMapper) SetWrapperType(typeStr string, wrapper bool) {
 	d.lock.Lock()
 	defer d.lock.Unlock()
 
 	d.wrapperMap[typeStr] = wrapper
+	atomic.AddUint64(&d.generation, 1)
 }
 
 func (d *defaultMapper) IsWrapperType(source reflect.Value) bool {
@@ -75,13 +184,35 @@ func (d *defaultMapper) IsWrapperType(source reflect.Value) bool {
 }
 
 func (d *defaultMapper) Map(source, dest interface{}, loose bool) {
-	var destType = reflect.TypeOf(dest)
-	if destType.Kind() != reflect.Ptr {
-		panic("Dest must be a pointer type")
+	if err := d.MapE(source, dest, loose); err != nil {
+		panic(err)
+	}
+}
+
+func (d *defaultMapper) MapE(source, dest interface{}, loose bool) error {
+	destType := reflect.TypeOf(dest)
+	if destType == nil || destType.Kind() != reflect.Ptr {
+		return &MappingError{DestType: destType, Reason: ErrNotPointer, Err: errors.New("dest must be a pointer type")}
 	}
-	var sourceVal = reflect.ValueOf(source)
-	var destVal = reflect.ValueOf(dest).Elem()
-	d.mapValues(sourceVal, destVal, loose)
+
+	sourceVal := reflect.ValueOf(source)
+	destVal := reflect.ValueOf(dest).Elem()
+	return d.mapValues(&mapCtx{loose: loose}, sourceVal, destVal)
+}
+
+func (d *defaultMapper) MapCollect(source, dest interface{}, loose bool) []*MappingError {
+	destType := reflect.TypeOf(dest)
+	if destType == nil || destType.Kind() != reflect.Ptr {
+		return []*MappingError{{DestType: destType, Reason: ErrNotPointer, Err: errors.New("dest must be a pointer type")}}
+	}
+
+	sourceVal := reflect.ValueOf(source)
+	destVal := reflect.ValueOf(dest).Elem()
+	ctx := &mapCtx{loose: loose, collect: &errorCollector{}}
+	if err := d.mapValues(ctx, sourceVal, destVal); err != nil {
+		ctx.collect.add(asMappingError(err))
+	}
+	return ctx.collect.errors
 }
 
 func (d *defaultMapper) RegisterMapping(from, to string, f MapperFunc) {
@@ -89,6 +220,7 @@ func (d *defaultMapper) RegisterMapping(from, to string, f MapperFunc) {
 	defer d.lock.Unlock()
 
 	d.valueMap[from+mapperConnector+to] = f
+	atomic.AddUint64(&d.generation, 1)
 }
 
 func (d *defaultMapper) UnregisterMapping(from, to string) {
@@ -97,9 +229,29 @@ func (d *defaultMapper) UnregisterMapping(from, to string) {
 
 	if _, ok := d.valueMap[from+mapperConnector+to]; ok {
 		delete(d.valueMap, from+mapperConnector+to)
+		atomic.AddUint64(&d.generation, 1)
 	}
 }
 
+func (d *defaultMapper) PrecompileMapping(source, dest interface{}) {
+	sourceType := reflect.TypeOf(source)
+	destType := reflect.TypeOf(dest)
+	if sourceType.Kind() == reflect.Ptr {
+		sourceType = sourceType.Elem()
+	}
+	if destType.Kind() == reflect.Ptr {
+		destType = destType.Elem()
+	}
+	if sourceType.Kind() != reflect.Struct || destType.Kind() != reflect.Struct {
+		return
+	}
+
+	d.getStructPlan(sourceType, destType, false)
+	d.getStructPlan(sourceType, destType, true)
+}
+
+var errNoConverterRegistered = errors.New("no converter registered")
+
 func (d *defaultMapper) mapCustom(source, destVal reflect.Value) error {
 	s := source.Type().String()
 	t := destVal.Type().String()
@@ -109,7 +261,7 @@ func (d *defaultMapper) mapCustom(source, destVal reflect.Value) error {
 
 	f, ok := d.valueMap[s+mapperConnector+t]
 	if !ok {
-		return errors.New(fmt.Sprintf("cannot find convertor for from %s to %s", s, t))
+		return fmt.Errorf("cannot find convertor for from %s to %s: %w", s, t, errNoConverterRegistered)
 	}
 
 	v, err := f(source.Interface())
@@ -121,20 +273,98 @@ func (d *defaultMapper) mapCustom(source, destVal reflect.Value) error {
 	return nil
 }
 
-func (d *defaultMapper) mapValues(sourceVal, destVal reflect.Value, loose bool) {
+// errorCollector accumulates MappingErrors across an entire MapCollect call.
+// It is shared (via a pointer held in mapCtx) across every recursive branch
+// so sibling fields/elements keep getting mapped after one of them fails.
+type errorCollector struct {
+	errors []*MappingError
+}
+
+func (ec *errorCollector) add(err *MappingError) {
+	ec.errors = append(ec.errors, err)
+}
+
+// mapCtx threads the loose flag and the dotted source/dest paths accumulated
+// so far through the recursive mapValues/mapSlice/mapMap/mapWrapper calls.
+// A non-nil collect means "keep going and record every failure" (MapCollect);
+// nil means "return the first error" (MapE/Map).
+type mapCtx struct {
+	loose      bool
+	sourcePath []string
+	destPath   []string
+	collect    *errorCollector
+}
+
+func (c *mapCtx) push(sourceNames, destNames []string) *mapCtx {
+	child := *c
+	if len(sourceNames) > 0 {
+		child.sourcePath = append(append([]string{}, c.sourcePath...), sourceNames...)
+	}
+	if len(destNames) > 0 {
+		child.destPath = append(append([]string{}, c.destPath...), destNames...)
+	}
+	return &child
+}
+
+func (c *mapCtx) newError(sourceVal, destVal reflect.Value, reason MappingErrorReason, err error) *MappingError {
+	return &MappingError{
+		SourcePath: strings.Join(c.sourcePath, "."),
+		DestPath:   strings.Join(c.destPath, "."),
+		SourceType: safeType(sourceVal),
+		DestType:   safeType(destVal),
+		Reason:     reason,
+		Err:        err,
+	}
+}
+
+// fail records err through the collector (and returns nil to keep the caller
+// looping) in collect mode, or simply returns err to abort in fail-fast mode.
+func (c *mapCtx) fail(err *MappingError) error {
+	if c.collect == nil {
+		return err
+	}
+	c.collect.add(err)
+	return nil
+}
+
+func safeType(v reflect.Value) reflect.Type {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Type()
+}
+
+var errWrapperTooManyFields = errors.New("too many field to mapper")
+
+func asMappingError(err error) *MappingError {
+	if me, ok := err.(*MappingError); ok {
+		return me
+	}
+	return &MappingError{Reason: ErrIncompatibleTypes, Err: err}
+}
+
+func (d *defaultMapper) mapValues(ctx *mapCtx, sourceVal, destVal reflect.Value) error {
 	destType := destVal.Type()
 	if d.IsWrapperType(sourceVal) || d.IsWrapperType(destVal) {
-		err := d.mapWrapper(sourceVal, destVal, loose)
-		if err == nil {
-			return
+		wrapErr := d.mapWrapper(ctx, sourceVal, destVal)
+		if wrapErr == nil {
+			return nil
 		}
-
-		err = d.mapCustom(sourceVal, destVal)
-		if err != nil {
-			panic("Failed to convert wrapper type: " + err.Error())
+		if !errors.Is(wrapErr, errWrapperTooManyFields) {
+			// wrapErr is a genuine mapping failure from the unwrapped value
+			// (missing field, required violation, nested converter failure,
+			// ...), not the wrapper itself failing to resolve. Propagate it
+			// as-is instead of discarding it and retrying mapCustom on the
+			// still-wrapped types.
+			return wrapErr
 		}
+		if convErr := d.mapCustom(sourceVal, destVal); convErr != nil {
+			return ctx.fail(ctx.newError(sourceVal, destVal, ErrWrapperTooManyFields, convErr))
+		}
+		return nil
 	} else if destType == sourceVal.Type() {
 		destVal.Set(sourceVal)
+		return nil
 	} else if destType.Kind() == reflect.Struct {
 		if sourceVal.Type().Kind() == reflect.Ptr {
 			if sourceVal.IsNil() {
@@ -143,117 +373,679 @@ func (d *defaultMapper) mapValues(sourceVal, destVal reflect.Value, loose bool)
 			}
 			sourceVal = sourceVal.Elem()
 		}
-		for i := 0; i < destVal.NumField(); i++ {
-			d.mapField(sourceVal, destVal, i, loose)
+		if sourceVal.Kind() == reflect.Map {
+			return d.mapMapToStruct(ctx, sourceVal, destVal)
 		}
+		return d.mapStructWithPlan(ctx, sourceVal, destVal)
 	} else if destType.Kind() == reflect.Ptr {
 		if d.valueIsNil(sourceVal) {
-			return
+			return nil
 		}
 		val := reflect.New(destType.Elem())
-		d.mapValues(sourceVal, val.Elem(), loose)
+		if err := d.mapValues(ctx, sourceVal, val.Elem()); err != nil {
+			return err
+		}
 		destVal.Set(val)
+		return nil
 	} else if destType.Kind() == reflect.Slice {
-		d.mapSlice(sourceVal, destVal, loose)
-	} else {
-		err := d.mapCustom(sourceVal, destVal)
-		if err != nil {
-			panic("Currently not supported: " + err.Error())
-		}
+		return d.mapSlice(ctx, sourceVal, destVal)
+	} else if destType.Kind() == reflect.Map {
+		return d.mapMap(ctx, sourceVal, destVal)
+	}
+
+	if err := d.mapCustom(sourceVal, destVal); err != nil {
+		return ctx.fail(ctx.newError(sourceVal, destVal, converterFailureReason(err), err))
+	}
+	return nil
+}
+
+func converterFailureReason(err error) MappingErrorReason {
+	if errors.Is(err, errNoConverterRegistered) {
+		return ErrIncompatibleTypes
 	}
+	return ErrConverterFailed
 }
 
-func (d *defaultMapper) mapSlice(sourceVal, destVal reflect.Value, loose bool) {
+func (d *defaultMapper) mapSlice(ctx *mapCtx, sourceVal, destVal reflect.Value) error {
 	destType := destVal.Type()
 	length := sourceVal.Len()
 	target := reflect.MakeSlice(destType, length, length)
 	for j := 0; j < length; j++ {
 		val := reflect.New(destType.Elem()).Elem()
-		d.mapValues(sourceVal.Index(j), val, loose)
+		elemPath := []string{fmt.Sprintf("[%d]", j)}
+		if err := d.mapValues(ctx.push(elemPath, elemPath), sourceVal.Index(j), val); err != nil {
+			return err
+		}
 		target.Index(j).Set(val)
 	}
 
 	if length == 0 {
-		d.verifyArrayTypesAreCompatible(sourceVal, destVal, loose)
+		if err := d.verifyArrayTypesAreCompatible(ctx, sourceVal, destVal); err != nil {
+			return err
+		}
 	}
 	destVal.Set(target)
+	return nil
 }
 
-func (d *defaultMapper) verifyArrayTypesAreCompatible(sourceVal, destVal reflect.Value, loose bool) {
+func (d *defaultMapper) verifyArrayTypesAreCompatible(ctx *mapCtx, sourceVal, destVal reflect.Value) error {
 	dummyDest := reflect.New(reflect.PtrTo(destVal.Type()))
 	dummySource := reflect.MakeSlice(sourceVal.Type(), 1, 1)
-	d.mapValues(dummySource, dummyDest.Elem(), loose)
+	return d.mapValues(ctx, dummySource, dummyDest.Elem())
+}
+
+func (d *defaultMapper) mapMap(ctx *mapCtx, sourceVal, destVal reflect.Value) error {
+	destType := destVal.Type()
+	if sourceVal.Kind() == reflect.Ptr {
+		if sourceVal.IsNil() {
+			sourceVal = reflect.New(sourceVal.Type().Elem())
+		}
+		sourceVal = sourceVal.Elem()
+	}
+
+	switch sourceVal.Kind() {
+	case reflect.Map:
+		target := reflect.MakeMapWithSize(destType, sourceVal.Len())
+		iter := sourceVal.MapRange()
+		for iter.Next() {
+			entryPath := []string{fmt.Sprintf("[%v]", iter.Key().Interface())}
+			entryCtx := ctx.push(entryPath, entryPath)
+
+			key := reflect.New(destType.Key()).Elem()
+			if err := d.mapValues(entryCtx, iter.Key(), key); err != nil {
+				return err
+			}
+			val := reflect.New(destType.Elem()).Elem()
+			if err := d.mapValues(entryCtx, iter.Value(), val); err != nil {
+				return err
+			}
+			target.SetMapIndex(key, val)
+		}
+
+		if sourceVal.Len() == 0 {
+			if err := d.verifyMapTypesAreCompatible(ctx, sourceVal, destVal); err != nil {
+				return err
+			}
+		}
+		destVal.Set(target)
+	case reflect.Struct:
+		tagName := d.getTagName()
+		target := reflect.MakeMapWithSize(destType, sourceVal.NumField())
+		for i := 0; i < sourceVal.NumField(); i++ {
+			srcField := sourceVal.Type().Field(i)
+			tag := parseMapperFieldTag(srcField.Tag.Get(tagName))
+			if tag.skip {
+				continue
+			}
+
+			keyName := srcField.Name
+			if tag.name != "" {
+				keyName = tag.name
+			}
+			fieldCtx := ctx.push([]string{srcField.Name}, []string{keyName})
+
+			key := reflect.New(destType.Key()).Elem()
+			if err := d.mapValues(fieldCtx, reflect.ValueOf(keyName), key); err != nil {
+				return err
+			}
+			val := reflect.New(destType.Elem()).Elem()
+			if err := d.mapValues(fieldCtx, sourceVal.Field(i), val); err != nil {
+				return err
+			}
+			target.SetMapIndex(key, val)
+		}
+		destVal.Set(target)
+	default:
+		if err := d.mapCustom(sourceVal, destVal); err != nil {
+			return ctx.fail(ctx.newError(sourceVal, destVal, converterFailureReason(err), err))
+		}
+	}
+	return nil
+}
+
+func (d *defaultMapper) mapMapToStruct(ctx *mapCtx, sourceVal, destVal reflect.Value) error {
+	destType := destVal.Type()
+	tagName := d.getTagName()
+	for i := 0; i < destVal.NumField(); i++ {
+		destField := destType.Field(i)
+		tag := parseMapperFieldTag(destField.Tag.Get(tagName))
+		if tag.skip {
+			continue
+		}
+
+		keyName := destField.Name
+		if tag.name != "" {
+			keyName = tag.name
+		}
+		fieldCtx := ctx.push([]string{keyName}, []string{destField.Name})
+
+		key := reflect.New(sourceVal.Type().Key()).Elem()
+		if err := d.mapValues(fieldCtx, reflect.ValueOf(keyName), key); err != nil {
+			return err
+		}
+
+		mapValue := sourceVal.MapIndex(key)
+		if !mapValue.IsValid() {
+			if tag.required {
+				if err := fieldCtx.fail(fieldCtx.newError(reflect.Value{}, destVal.Field(i), ErrMissingField, errors.New("required field has no counterpart in source map"))); err != nil {
+					return err
+				}
+				continue
+			}
+			if ctx.loose {
+				continue
+			}
+			if err := fieldCtx.fail(fieldCtx.newError(reflect.Value{}, destVal.Field(i), ErrMissingField, fmt.Errorf("cannot find key %s in source map", keyName))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := d.mapValues(fieldCtx, mapValue, destVal.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *defaultMapper) verifyMapTypesAreCompatible(ctx *mapCtx, sourceVal, destVal reflect.Value) error {
+	dummyDest := reflect.New(reflect.PtrTo(destVal.Type()))
+	dummySource := reflect.MakeMapWithSize(sourceVal.Type(), 1)
+	dummySource.SetMapIndex(reflect.New(sourceVal.Type().Key()).Elem(), reflect.New(sourceVal.Type().Elem()).Elem())
+	return d.mapValues(ctx, dummySource, dummyDest.Elem())
 }
 
-func (d *defaultMapper) mapWrapper(source, destVal reflect.Value, loose bool) error {
+func (d *defaultMapper) mapWrapper(ctx *mapCtx, source, destVal reflect.Value) error {
 	if d.IsWrapperType(source) {
 		if source.NumField() > 1 {
-			return errors.New("too many field to mapper for source")
+			return errWrapperTooManyFields
 		}
-		return d.mapWrapper(source.Field(0), destVal, loose)
+		return d.mapWrapper(ctx, source.Field(0), destVal)
 	}
 	if d.IsWrapperType(destVal) {
 		if destVal.NumField() > 1 {
-			return errors.New("too many field to mapper for dest val")
+			return errWrapperTooManyFields
 		}
-		return d.mapWrapper(source, destVal.Field(0), loose)
+		return d.mapWrapper(ctx, source, destVal.Field(0))
 	}
 
-	d.mapValues(source, destVal, loose)
-	return nil
+	return d.mapValues(ctx, source, destVal)
 }
 
-func (d *defaultMapper) mapField(source, destVal reflect.Value, i int, loose bool) {
-	destType := destVal.Type()
-	fieldName := destType.Field(i).Name
-	defer func() {
-		if r := recover(); r != nil {
-			panic(fmt.Sprintf("Error mapping field: %s. DestType: %v. SourceType: %v. Error: %v", fieldName, destType, source.Type(), r))
-		}
-	}()
-
-	destField := destVal.Field(i)
-	if destType.Field(i).Anonymous {
-		d.mapValues(source, destField, loose)
-	} else {
-		if d.valueIsContainedInNilEmbeddedType(source, fieldName) {
-			return
-		}
-		sourceField := source.FieldByName(fieldName)
-		if (sourceField == reflect.Value{}) {
-			if loose {
-				return
-			}
-			if destField.Kind() == reflect.Struct {
-				d.mapValues(source, destField, loose)
-				return
-			} else {
-				for i := 0; i < source.NumField(); i++ {
-					if source.Field(i).Kind() != reflect.Struct {
-						continue
+// fieldPlanKind records how a dest struct field's source instruction was
+// resolved so that repeated Map calls for the same (source, dest, loose)
+// tuple can skip straight to a FieldByIndex lookup instead of re-probing
+// field names and embedded fallbacks every time.
+type fieldPlanKind int
+
+const (
+	fieldPlanAnonymous fieldPlanKind = iota
+	fieldPlanDirect
+	fieldPlanNestedFallback
+	fieldPlanEmbeddedFallback
+	fieldPlanSkip
+	fieldPlanMissing
+	fieldPlanRequiredMissing
+)
+
+// mapperFieldTag is the parsed form of a `mapper:"..."` struct tag: a leading
+// directive (a rename, a "Parent.Child" dotted source path, or "-" to skip)
+// followed by comma-separated flags ("required", "inline").
+type mapperFieldTag struct {
+	name     string
+	path     []string
+	skip     bool
+	required bool
+	inline   bool
+}
+
+func parseMapperFieldTag(raw string) mapperFieldTag {
+	if raw == "" {
+		return mapperFieldTag{}
+	}
+
+	parts := strings.Split(raw, ",")
+	tag := mapperFieldTag{}
+	switch directive := parts[0]; {
+	case directive == "-":
+		tag.skip = true
+	case strings.Contains(directive, "."):
+		tag.path = strings.Split(directive, ".")
+	case directive != "":
+		tag.name = directive
+	}
+
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "required":
+			tag.required = true
+		case "inline":
+			tag.inline = true
+		}
+	}
+	return tag
+}
+
+// visibleFieldIndex is a cached, ambiguity-aware view of a struct type's
+// exported fields following Go's own embedding/selector promotion rules to
+// arbitrary depth. A name reachable through more than one path at the
+// shallowest depth is ambiguous per Go's own selector rules - reflect.VisibleFields
+// drops it entirely rather than reporting it, so buildVisibleFieldIndex walks
+// the type itself to keep one of the colliding candidates in anyMatch, letting
+// loose resolution pick it instead of treating the field as wholly absent.
+type visibleFieldIndex struct {
+	unambiguous map[string][]int
+	anyMatch    map[string][]int
+}
+
+func (idx *visibleFieldIndex) resolve(name string, loose bool) ([]int, bool) {
+	if path, ok := idx.unambiguous[name]; ok {
+		return path, true
+	}
+	if loose {
+		if path, ok := idx.anyMatch[name]; ok {
+			return path, true
+		}
+	}
+	return nil, false
+}
+
+func (d *defaultMapper) getVisibleFieldIndex(t reflect.Type) *visibleFieldIndex {
+	if cached, ok := d.visibleFieldCache.Load(t); ok {
+		return cached.(*visibleFieldIndex)
+	}
+	idx := buildVisibleFieldIndex(t)
+	actual, _ := d.visibleFieldCache.LoadOrStore(t, idx)
+	return actual.(*visibleFieldIndex)
+}
+
+// fieldAtDepth is one candidate index path found for a field name while
+// walking breadth-first by embedding depth. onPath holds the struct types
+// already entered along index, so a pointer-embedding cycle stops there.
+type fieldAtDepth struct {
+	t      reflect.Type
+	index  []int
+	onPath []reflect.Type
+}
+
+func buildVisibleFieldIndex(t reflect.Type) *visibleFieldIndex {
+	idx := &visibleFieldIndex{unambiguous: map[string][]int{}, anyMatch: map[string][]int{}}
+	queue := []fieldAtDepth{{t, nil, []reflect.Type{t}}}
+	resolved := map[string]bool{}
+
+	for len(queue) > 0 {
+		var next []fieldAtDepth
+		candidates := map[string][][]int{}
+		for _, level := range queue {
+			for i := 0; i < level.t.NumField(); i++ {
+				f := level.t.Field(i)
+				fieldIndex := append(append([]int{}, level.index...), i)
+				if f.IsExported() && !resolved[f.Name] {
+					candidates[f.Name] = append(candidates[f.Name], fieldIndex)
+				}
+				if f.Anonymous {
+					ft := f.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
 					}
-					if sourceField = source.Field(i).FieldByName(fieldName); (sourceField != reflect.Value{}) {
-						break
+					cycle := false
+					for _, seen := range level.onPath {
+						if seen == ft {
+							cycle = true
+							break
+						}
+					}
+					if ft.Kind() == reflect.Struct && !cycle {
+						next = append(next, fieldAtDepth{ft, fieldIndex, append(append([]reflect.Type{}, level.onPath...), ft)})
 					}
 				}
 			}
 		}
-		d.mapValues(sourceField, destField, loose)
+		for name, paths := range candidates {
+			resolved[name] = true
+			idx.anyMatch[name] = paths[0]
+			if len(paths) == 1 {
+				idx.unambiguous[name] = paths[0]
+			}
+		}
+		queue = next
 	}
+	return idx
 }
 
-func (d *defaultMapper) valueIsNil(value reflect.Value) bool {
-	return value.Type().Kind() == reflect.Ptr && value.IsNil()
+// fieldByIndexSafe walks index the way reflect.Value.FieldByIndex does, but
+// stops and reports ok=false the moment it would have to dereference a nil
+// pointer partway through the path instead of panicking. This lets a nil
+// embedded/nested pointer anywhere along a resolved field path be treated as
+// "field not present" rather than crashing.
+func fieldByIndexSafe(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
 }
 
-func (d *defaultMapper) valueIsContainedInNilEmbeddedType(source reflect.Value, fieldName string) bool {
-	structField, _ := source.Type().FieldByName(fieldName)
-	ix := structField.Index
-	if len(structField.Index) > 1 {
-		parentField := source.FieldByIndex(ix[:len(ix)-1])
-		if d.valueIsNil(parentField) {
-			return true
+// sourceFieldIndex indexes a source struct type's fields so buildStructPlan
+// can honor tag-driven renames and "inline" flattening without re-walking
+// NumField() for every dest field.
+type sourceFieldIndex struct {
+	renamed map[string][]int
+	skipped map[string]bool
+	inline  []reflect.StructField
+}
+
+func (d *defaultMapper) buildSourceFieldIndex(sourceType reflect.Type, tagName string) sourceFieldIndex {
+	idx := sourceFieldIndex{renamed: map[string][]int{}, skipped: map[string]bool{}}
+	for j := 0; j < sourceType.NumField(); j++ {
+		field := sourceType.Field(j)
+		tag := parseMapperFieldTag(field.Tag.Get(tagName))
+		if tag.skip {
+			idx.skipped[field.Name] = true
+			continue
+		}
+		if tag.name != "" {
+			idx.renamed[tag.name] = field.Index
+		}
+		if tag.inline && field.Type.Kind() == reflect.Struct {
+			idx.inline = append(idx.inline, field)
+		}
+	}
+	return idx
+}
+
+// resolveSourceField finds the index path for name, preferring a source
+// field explicitly renamed to it, then Go's own (embedding-aware) field
+// resolution via the cached visibleFieldIndex, then one level into any
+// `mapper:",inline"` source fields. A source field tagged `mapper:"-"` is
+// excluded even though it would otherwise match by name. loose controls
+// whether a name that is ambiguous per Go's own selector rules (reachable
+// through more than one embedded field at the same depth) is still resolved.
+func (d *defaultMapper) resolveSourceField(sourceType reflect.Type, idx sourceFieldIndex, name string, loose bool) ([]int, bool) {
+	if path, ok := idx.renamed[name]; ok {
+		return path, true
+	}
+	if idx.skipped[name] {
+		return nil, false
+	}
+	if path, ok := d.getVisibleFieldIndex(sourceType).resolve(name, loose); ok {
+		return path, true
+	}
+	for _, inlineField := range idx.inline {
+		if path, ok := d.getVisibleFieldIndex(inlineField.Type).resolve(name, loose); ok {
+			return append(append([]int{}, inlineField.Index...), path...), true
+		}
+	}
+	return nil, false
+}
+
+func resolveDottedSourcePath(sourceType reflect.Type, path []string) ([]int, bool) {
+	var index []int
+	t := sourceType
+	for _, part := range path {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		field, ok := t.FieldByName(part)
+		if !ok {
+			return nil, false
+		}
+		index = append(index, field.Index...)
+		t = field.Type
+	}
+	return index, true
+}
+
+// fieldIndexPathNames resolves the field names along index, used to report
+// an accurate dotted source path on error.
+func fieldIndexPathNames(t reflect.Type, index []int) []string {
+	names := make([]string, 0, len(index))
+	cur := t
+	for _, i := range index {
+		if cur.Kind() == reflect.Ptr {
+			cur = cur.Elem()
+		}
+		field := cur.Field(i)
+		names = append(names, field.Name)
+		cur = field.Type
+	}
+	return names
+}
+
+type fieldPlan struct {
+	destIndex   int
+	kind        fieldPlanKind
+	sourceIndex []int
+
+	// resolved, staticWrapper and converter are filled in once, when the plan
+	// is built, from the statically known (non-interface) source/dest field
+	// types. When resolved is true, applyFieldPlan's direct-copy case can
+	// trust staticWrapper/converter instead of re-taking d.lock to call
+	// IsWrapperType/mapCustom on every single field of every Map call.
+	// resolved stays false for interface-typed fields, whose dynamic type
+	// isn't known until the value is in hand, so those still fall back to
+	// the per-value checks in mapValues.
+	resolved      bool
+	staticWrapper bool
+	converter     MapperFunc
+}
+
+type mappingPlan struct {
+	fields []fieldPlan
+}
+
+type planKey struct {
+	source reflect.Type
+	dest   reflect.Type
+	loose  bool
+}
+
+type cachedPlan struct {
+	generation uint64
+	plan       *mappingPlan
+}
+
+func (d *defaultMapper) getStructPlan(sourceType, destType reflect.Type, loose bool) *mappingPlan {
+	key := planKey{sourceType, destType, loose}
+	gen := atomic.LoadUint64(&d.generation)
+
+	if cached, ok := d.plans.Load(key); ok {
+		if cp := cached.(*cachedPlan); cp.generation == gen {
+			return cp.plan
 		}
 	}
-	return false
+
+	plan := d.buildStructPlan(sourceType, destType, loose)
+	d.plans.Store(key, &cachedPlan{generation: gen, plan: plan})
+	return plan
+}
+
+// resolveFieldPlanStatics resolves, once at plan-build time, whether a direct
+// source/dest field pair is a wrapper unwrap or has a registered converter,
+// so the hot path doesn't need to take d.lock per field on every Map call.
+// It returns resolved=false for interface-typed fields, since their dynamic
+// type isn't known until the value is in hand.
+func (d *defaultMapper) resolveFieldPlanStatics(sourceFieldType, destFieldType reflect.Type) (resolved, staticWrapper bool, converter MapperFunc) {
+	if sourceFieldType.Kind() == reflect.Interface || destFieldType.Kind() == reflect.Interface {
+		return false, false, nil
+	}
+
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	if w, ok := d.wrapperMap[sourceFieldType.String()]; ok && w {
+		return true, true, nil
+	}
+	if w, ok := d.wrapperMap[destFieldType.String()]; ok && w {
+		return true, true, nil
+	}
+	if f, ok := d.valueMap[sourceFieldType.String()+mapperConnector+destFieldType.String()]; ok {
+		return true, false, f
+	}
+	return true, false, nil
+}
+
+func (d *defaultMapper) directFieldPlan(destIndex int, sourceType, destType reflect.Type, sourceIndex []int, kind fieldPlanKind) fieldPlan {
+	sourceFieldType := sourceType.FieldByIndex(sourceIndex).Type
+	destFieldType := destType.Field(destIndex).Type
+	resolved, staticWrapper, converter := d.resolveFieldPlanStatics(sourceFieldType, destFieldType)
+	return fieldPlan{
+		destIndex:     destIndex,
+		kind:          kind,
+		sourceIndex:   sourceIndex,
+		resolved:      resolved,
+		staticWrapper: staticWrapper,
+		converter:     converter,
+	}
+}
+
+func (d *defaultMapper) buildStructPlan(sourceType, destType reflect.Type, loose bool) *mappingPlan {
+	tagName := d.getTagName()
+	srcIdx := d.buildSourceFieldIndex(sourceType, tagName)
+
+	plan := &mappingPlan{fields: make([]fieldPlan, destType.NumField())}
+	for i := 0; i < destType.NumField(); i++ {
+		destField := destType.Field(i)
+		tag := parseMapperFieldTag(destField.Tag.Get(tagName))
+
+		if tag.skip {
+			plan.fields[i] = fieldPlan{destIndex: i, kind: fieldPlanSkip}
+			continue
+		}
+
+		if destField.Anonymous || (tag.inline && destField.Type.Kind() == reflect.Struct) {
+			plan.fields[i] = fieldPlan{destIndex: i, kind: fieldPlanAnonymous}
+			continue
+		}
+
+		if len(tag.path) > 0 {
+			if sourceIndex, ok := resolveDottedSourcePath(sourceType, tag.path); ok {
+				plan.fields[i] = d.directFieldPlan(i, sourceType, destType, sourceIndex, fieldPlanDirect)
+				continue
+			}
+			plan.fields[i] = d.missingFieldPlan(i, tag, loose)
+			continue
+		}
+
+		lookupName := destField.Name
+		if tag.name != "" {
+			lookupName = tag.name
+		}
+
+		if sourceIndex, ok := d.resolveSourceField(sourceType, srcIdx, lookupName, loose); ok {
+			plan.fields[i] = d.directFieldPlan(i, sourceType, destType, sourceIndex, fieldPlanDirect)
+			continue
+		}
+
+		if tag.required {
+			plan.fields[i] = fieldPlan{destIndex: i, kind: fieldPlanRequiredMissing}
+			continue
+		}
+
+		if loose {
+			plan.fields[i] = fieldPlan{destIndex: i, kind: fieldPlanSkip}
+			continue
+		}
+
+		if destField.Type.Kind() == reflect.Struct {
+			plan.fields[i] = fieldPlan{destIndex: i, kind: fieldPlanNestedFallback}
+			continue
+		}
+
+		plan.fields[i] = fieldPlan{destIndex: i, kind: fieldPlanMissing}
+		var matches [][]int
+		for j := 0; j < sourceType.NumField(); j++ {
+			if sourceType.Field(j).Type.Kind() != reflect.Struct {
+				continue
+			}
+			if sourceField, ok := sourceType.Field(j).Type.FieldByName(destField.Name); ok {
+				matches = append(matches, append([]int{j}, sourceField.Index...))
+			}
+		}
+		if len(matches) == 1 || (len(matches) > 1 && loose) {
+			plan.fields[i] = d.directFieldPlan(i, sourceType, destType, matches[0], fieldPlanEmbeddedFallback)
+		}
+	}
+	return plan
+}
+
+func (d *defaultMapper) missingFieldPlan(destIndex int, tag mapperFieldTag, loose bool) fieldPlan {
+	if tag.required {
+		return fieldPlan{destIndex: destIndex, kind: fieldPlanRequiredMissing}
+	}
+	if loose {
+		return fieldPlan{destIndex: destIndex, kind: fieldPlanSkip}
+	}
+	return fieldPlan{destIndex: destIndex, kind: fieldPlanMissing}
+}
+
+func (d *defaultMapper) mapStructWithPlan(ctx *mapCtx, sourceVal, destVal reflect.Value) error {
+	plan := d.getStructPlan(sourceVal.Type(), destVal.Type(), ctx.loose)
+	for _, f := range plan.fields {
+		if err := d.applyFieldPlan(ctx, sourceVal, destVal, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *defaultMapper) applyFieldPlan(ctx *mapCtx, sourceVal, destVal reflect.Value, f fieldPlan) error {
+	destType := destVal.Type()
+	destName := destType.Field(f.destIndex).Name
+	destField := destVal.Field(f.destIndex)
+
+	switch f.kind {
+	case fieldPlanSkip:
+		return nil
+	case fieldPlanMissing:
+		fieldCtx := ctx.push(nil, []string{destName})
+		return fieldCtx.fail(fieldCtx.newError(reflect.Value{}, destField, ErrMissingField, errors.New("no matching field on source")))
+	case fieldPlanRequiredMissing:
+		fieldCtx := ctx.push(nil, []string{destName})
+		return fieldCtx.fail(fieldCtx.newError(reflect.Value{}, destField, ErrMissingField, errors.New("required field has no counterpart on source")))
+	case fieldPlanAnonymous, fieldPlanNestedFallback:
+		fieldCtx := ctx.push(nil, []string{destName})
+		return d.mapValues(fieldCtx, sourceVal, destField)
+	case fieldPlanDirect, fieldPlanEmbeddedFallback:
+		sourceField, ok := fieldByIndexSafe(sourceVal, f.sourceIndex)
+		if !ok {
+			return nil
+		}
+		sourceNames := fieldIndexPathNames(sourceVal.Type(), f.sourceIndex)
+		fieldCtx := ctx.push(sourceNames, []string{destName})
+
+		if !f.resolved || f.staticWrapper {
+			// Not statically resolvable (interface-typed field) or resolved
+			// to a wrapper unwrap, which needs mapWrapper's recursive logic.
+			return d.mapValues(fieldCtx, sourceField, destField)
+		}
+		if destField.Type() == sourceField.Type() {
+			destField.Set(sourceField)
+			return nil
+		}
+		switch destField.Kind() {
+		case reflect.Struct, reflect.Ptr, reflect.Slice, reflect.Map:
+			return d.mapValues(fieldCtx, sourceField, destField)
+		}
+		if f.converter == nil {
+			err := fmt.Errorf("cannot find convertor for from %s to %s: %w", sourceField.Type().String(), destField.Type().String(), errNoConverterRegistered)
+			return fieldCtx.fail(fieldCtx.newError(sourceField, destField, ErrIncompatibleTypes, err))
+		}
+		v, err := f.converter(sourceField.Interface())
+		if err != nil {
+			return fieldCtx.fail(fieldCtx.newError(sourceField, destField, ErrConverterFailed, err))
+		}
+		destField.Set(reflect.ValueOf(v))
+		return nil
+	}
+	return nil
+}
+
+func (d *defaultMapper) valueIsNil(value reflect.Value) bool {
+	return value.Type().Kind() == reflect.Ptr && value.IsNil()
 }
@@ -1,6 +1,8 @@
 package go_mapper
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -96,6 +98,34 @@ func TestMapWithWrapper(t *testing.T) {
 	assert.Equal(t, sourceA.Time.T, targetA.Time, "did not map time")
 }
 
+type WrapperInnerMismatch struct {
+	Foo int
+}
+
+type WrapperOuterMismatch struct {
+	Inner WrapperInnerMismatch
+}
+
+type WrapperDestMismatch struct {
+	Foo int
+	Bar string
+}
+
+func TestMapEWithWrapperPropagatesGenuineNestedError(t *testing.T) {
+	SetWrapperType("go_mapper.WrapperOuterMismatch", true)
+	defer SetWrapperType("go_mapper.WrapperOuterMismatch", false)
+
+	source := WrapperOuterMismatch{Inner: WrapperInnerMismatch{Foo: 1}}
+	dest := WrapperDestMismatch{}
+
+	err := MapE(source, &dest, false)
+
+	var mappingErr *MappingError
+	assert.ErrorAs(t, err, &mappingErr)
+	assert.Equal(t, ErrMissingField, mappingErr.Reason)
+	assert.Equal(t, "Bar", mappingErr.DestPath)
+}
+
 func TestPanicWhenDestIsNotPointer(t *testing.T) {
 	defer func() { recover() }()
 	source, dest := SourceTypeA{}, DestTypeA{}
@@ -432,6 +462,388 @@ func TestMapWithSameSourceTypePtr(t *testing.T) {
 	assert.Equal(t, source.B.Bar, dest.B.Bar, "cannot map bar")
 }
 
+func TestMapWithMapOfStructs(t *testing.T) {
+	source := map[string]SourceTypeA{
+		"a": {Foo: 1, Bar: "one"},
+		"b": {Foo: 2, Bar: "two"},
+	}
+	dest := map[string]DestTypeA{}
+
+	Map(source, &dest, false)
+	assert.Equal(t, 1, dest["a"].Foo)
+	assert.Equal(t, "one", dest["a"].Bar)
+	assert.Equal(t, 2, dest["b"].Foo)
+	assert.Equal(t, "two", dest["b"].Bar)
+}
+
+func TestMapWithNestedMaps(t *testing.T) {
+	source := map[string]map[string]SourceTypeA{
+		"outer": {
+			"inner": {Foo: 1, Bar: "one"},
+		},
+	}
+	dest := map[string]map[string]DestTypeA{}
+
+	Map(source, &dest, false)
+	assert.Equal(t, 1, dest["outer"]["inner"].Foo)
+	assert.Equal(t, "one", dest["outer"]["inner"].Bar)
+}
+
+func TestMapWithMapKeyTypeConversion(t *testing.T) {
+	RegisterMapping("int", "string", func(v any) (any, error) {
+		return fmt.Sprintf("%d", v.(int)), nil
+	})
+	defer UnregisterMapping("int", "string")
+
+	source := map[int]SourceTypeA{
+		1: {Foo: 1, Bar: "one"},
+	}
+	dest := map[string]DestTypeA{}
+
+	Map(source, &dest, false)
+	assert.Equal(t, 1, dest["1"].Foo)
+	assert.Equal(t, "one", dest["1"].Bar)
+}
+
+func TestMapWithStructToMap(t *testing.T) {
+	source := SourceTypeA{Foo: 1, Bar: "one"}
+	dest := map[string]string{}
+
+	RegisterMapping("int", "string", func(v any) (any, error) {
+		return fmt.Sprintf("%d", v.(int)), nil
+	})
+	defer UnregisterMapping("int", "string")
+
+	Map(source, &dest, false)
+	assert.Equal(t, "1", dest["Foo"])
+	assert.Equal(t, "one", dest["Bar"])
+}
+
+func TestMapWithMapToStruct(t *testing.T) {
+	source := map[string]string{
+		"Bar": "bar",
+	}
+	dest := DestTypeBNest{}
+
+	Map(source, &dest, true)
+	assert.Equal(t, "bar", dest.Bar)
+}
+
+func TestMapWithMapToStructMissingKeyPanics(t *testing.T) {
+	defer func() { recover() }()
+
+	source := map[string]string{}
+	dest := DestTypeA{}
+
+	Map(source, &dest, false)
+	t.Error("Should have panicked")
+}
+
+type TagMapDest struct {
+	Foo string `mapper:"OtherKey"`
+	Bar string `mapper:"-"`
+	Baz string `mapper:",required"`
+}
+
+func TestMapWithMapToStructHonorsTagRename(t *testing.T) {
+	source := map[string]string{"OtherKey": "hello", "Baz": "z"}
+	dest := TagMapDest{}
+
+	Map(source, &dest, true)
+	assert.Equal(t, "hello", dest.Foo)
+}
+
+func TestMapWithMapToStructHonorsTagSkip(t *testing.T) {
+	source := map[string]string{"OtherKey": "hello", "Baz": "z"}
+	dest := TagMapDest{}
+
+	Map(source, &dest, true)
+	assert.Equal(t, "", dest.Bar)
+}
+
+func TestMapWithMapToStructTagRequiredPanicsEvenWhenLoose(t *testing.T) {
+	defer func() { recover() }()
+
+	source := map[string]string{"OtherKey": "hello"}
+	dest := TagMapDest{}
+
+	Map(source, &dest, true)
+	t.Error("Should have panicked")
+}
+
+type TagStructToMapSource struct {
+	Foo string `mapper:"OtherKey"`
+	Bar string `mapper:"-"`
+}
+
+func TestMapWithStructToMapHonorsTagRenameAndSkip(t *testing.T) {
+	source := TagStructToMapSource{Foo: "hello", Bar: "bar"}
+	dest := map[string]string{}
+
+	Map(source, &dest, false)
+	assert.Equal(t, "hello", dest["OtherKey"])
+	assert.Equal(t, 1, len(dest))
+}
+
+type TagSourceRename struct {
+	Foo int `mapper:"Renamed"`
+	Bar string
+}
+
+type TagDestRename struct {
+	Renamed int
+	Bar     string
+}
+
+func TestMapWithTagRenameOnSourceField(t *testing.T) {
+	source := TagSourceRename{Foo: 42, Bar: "bar"}
+	dest := TagDestRename{}
+
+	Map(source, &dest, false)
+	assert.Equal(t, 42, dest.Renamed)
+	assert.Equal(t, "bar", dest.Bar)
+}
+
+type TagDestRenameField struct {
+	Foo string `mapper:"Bar"`
+}
+
+func TestMapWithTagRenameOnDestField(t *testing.T) {
+	source := SourceTypeA{Foo: 1, Bar: "bar"}
+	dest := TagDestRenameField{}
+
+	Map(source, &dest, false)
+	assert.Equal(t, "bar", dest.Foo)
+}
+
+type TagSkipSource struct {
+	Foo int
+	Bar string `mapper:"-"`
+}
+
+type TagSkipDestMatch struct {
+	Foo int
+	Bar string
+}
+
+func TestMapWithTagSkipOnSourceField(t *testing.T) {
+	source := TagSkipSource{Foo: 1, Bar: "bar"}
+	dest := TagSkipDestMatch{}
+
+	Map(source, &dest, true)
+	assert.Equal(t, 1, dest.Foo)
+	assert.Equal(t, "", dest.Bar)
+}
+
+type TagSkipDest struct {
+	Foo int
+	Bar string `mapper:"-"`
+}
+
+func TestMapWithTagSkipOnDestField(t *testing.T) {
+	source := SourceTypeA{Foo: 1, Bar: "bar"}
+	dest := TagSkipDest{}
+
+	Map(source, &dest, false)
+	assert.Equal(t, 1, dest.Foo)
+	assert.Equal(t, "", dest.Bar)
+}
+
+type TagRequiredDest struct {
+	Foo int
+	Baz string `mapper:",required"`
+}
+
+func TestMapWithTagRequiredFieldPanicsEvenWhenLoose(t *testing.T) {
+	defer func() { recover() }()
+
+	source := SourceTypeA{Foo: 1, Bar: "bar"}
+	dest := TagRequiredDest{}
+
+	Map(source, &dest, true)
+	t.Error("Should have panicked")
+}
+
+type TagNestedParent struct {
+	Child SourceTypeA
+}
+
+type TagDottedDest struct {
+	ChildBar string `mapper:"Child.Bar"`
+}
+
+func TestMapWithTagDottedPath(t *testing.T) {
+	source := TagNestedParent{Child: SourceTypeA{Foo: 1, Bar: "bar"}}
+	dest := TagDottedDest{}
+
+	Map(source, &dest, false)
+	assert.Equal(t, "bar", dest.ChildBar)
+}
+
+type TagDottedNilEmbeddedDest struct {
+	ChildBar string `mapper:"Child.Bar"`
+}
+
+func TestMapWithTagDottedPathHonorsNilEmbedded(t *testing.T) {
+	source := struct {
+		Child *SourceTypeA
+	}{}
+	dest := TagDottedNilEmbeddedDest{}
+
+	Map(&source, &dest, false)
+	assert.Equal(t, "", dest.ChildBar)
+}
+
+func TestMapEReturnsErrorInsteadOfPanicking(t *testing.T) {
+	source, dest := SourceTypeA{}, DestTypeA{}
+	err := MapE(source, dest, false)
+
+	var mappingErr *MappingError
+	assert.ErrorAs(t, err, &mappingErr)
+	assert.Equal(t, ErrNotPointer, mappingErr.Reason)
+}
+
+func TestMapEReturnsStructuredErrorForMissingField(t *testing.T) {
+	source := struct {
+		A string
+	}{A: "a"}
+	dest := struct {
+		A, B string
+	}{}
+
+	err := MapE(&source, &dest, false)
+
+	var mappingErr *MappingError
+	assert.ErrorAs(t, err, &mappingErr)
+	assert.Equal(t, ErrMissingField, mappingErr.Reason)
+	assert.Equal(t, "B", mappingErr.DestPath)
+}
+
+func TestMapEReturnsNilOnSuccess(t *testing.T) {
+	source, dest := SourceTypeA{Foo: 1, Bar: "bar"}, DestTypeA{}
+	err := MapE(source, &dest, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dest.Foo)
+	assert.Equal(t, "bar", dest.Bar)
+}
+
+func TestMapEReportsDottedPathThroughNestedStructs(t *testing.T) {
+	source := struct {
+		Child struct {
+			A string
+		}
+	}{}
+	dest := struct {
+		Child struct {
+			A, B string
+		}
+	}{}
+
+	err := MapE(&source, &dest, false)
+
+	var mappingErr *MappingError
+	assert.ErrorAs(t, err, &mappingErr)
+	assert.Equal(t, "Child.B", mappingErr.DestPath)
+}
+
+func TestMapCollectAccumulatesEveryMissingField(t *testing.T) {
+	source := struct {
+		A string
+	}{A: "a"}
+	dest := struct {
+		A, B, C string
+	}{}
+
+	errs := MapCollect(&source, &dest, false)
+
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "B", errs[0].DestPath)
+	assert.Equal(t, "C", errs[1].DestPath)
+}
+
+type AmbiguousLeft struct {
+	Name string
+}
+
+type AmbiguousRight struct {
+	Name string
+}
+
+type AmbiguousSource struct {
+	AmbiguousLeft
+	AmbiguousRight
+}
+
+type AmbiguousDest struct {
+	Name string
+}
+
+func TestMapWithAmbiguousPromotedFieldSkippedUnlessLoose(t *testing.T) {
+	source := AmbiguousSource{AmbiguousLeft{Name: "left"}, AmbiguousRight{Name: "right"}}
+	dest := AmbiguousDest{}
+
+	err := MapE(source, &dest, false)
+
+	var mappingErr *MappingError
+	assert.ErrorAs(t, err, &mappingErr)
+	assert.Equal(t, "Name", mappingErr.DestPath)
+}
+
+func TestMapWithAmbiguousPromotedFieldResolvedWhenLoose(t *testing.T) {
+	source := AmbiguousSource{AmbiguousLeft{Name: "left"}, AmbiguousRight{Name: "right"}}
+	dest := AmbiguousDest{}
+
+	Map(source, &dest, true)
+	assert.True(t, dest.Name == "left" || dest.Name == "right")
+}
+
+type DeepEmbedInner struct {
+	Value string
+}
+
+type DeepEmbedMiddle struct {
+	DeepEmbedInner
+}
+
+type DeepEmbedOuter struct {
+	DeepEmbedMiddle
+}
+
+type DeepEmbedDest struct {
+	Value string
+}
+
+func TestMapWithTwoLevelDeepEmbedding(t *testing.T) {
+	source := DeepEmbedOuter{DeepEmbedMiddle{DeepEmbedInner{Value: "deep"}}}
+	dest := DeepEmbedDest{}
+
+	Map(source, &dest, false)
+	assert.Equal(t, "deep", dest.Value)
+}
+
+func TestMapCollectReturnsEmptySliceOnSuccess(t *testing.T) {
+	source, dest := SourceTypeA{Foo: 1, Bar: "bar"}, DestTypeA{}
+	errs := MapCollect(source, &dest, false)
+
+	assert.Empty(t, errs)
+	assert.Equal(t, 1, dest.Foo)
+}
+
+func TestMapCollectContinuesPastFailingSliceElements(t *testing.T) {
+	source := struct {
+		Children []struct{ A string }
+	}{Children: []struct{ A string }{{A: "one"}, {A: "two"}}}
+	dest := struct {
+		Children []struct{ A, B string }
+	}{}
+
+	errs := MapCollect(&source, &dest, false)
+
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "two", dest.Children[1].A)
+}
+
 func TestMapWithSameTypeBothPtr(t *testing.T) {
 	source := SourceTypeBPtr{
 		SourceTypeA{
@@ -451,3 +863,100 @@ func TestMapWithSameTypeBothPtr(t *testing.T) {
 	assert.Equal(t, source.B.Foo, dest.B.Foo, "cannot map bar")
 	assert.Equal(t, source.B.Bar, dest.B.Bar, "cannot map bar")
 }
+
+type PrecompileSource struct {
+	Foo int
+}
+
+type PrecompileDest struct {
+	Foo int
+}
+
+func TestPrecompileMappingWarmsPlanCacheForSubsequentMap(t *testing.T) {
+	d := NewDefaultMapper().(*defaultMapper)
+	source := PrecompileSource{Foo: 1}
+
+	d.PrecompileMapping(source, PrecompileDest{})
+
+	key := planKey{source: reflect.TypeOf(source), dest: reflect.TypeOf(PrecompileDest{}), loose: false}
+	_, ok := d.plans.Load(key)
+	assert.True(t, ok, "PrecompileMapping should have warmed the plan cache")
+
+	dest := PrecompileDest{}
+	d.Map(source, &dest, false)
+	assert.Equal(t, 1, dest.Foo)
+}
+
+type CustomTagSource struct {
+	Foo int `myTag:"Renamed"`
+}
+
+type CustomTagDest struct {
+	Renamed int
+}
+
+func TestSetTagNameUsesCustomTagKey(t *testing.T) {
+	d := NewDefaultMapper().(*defaultMapper)
+	d.SetTagName("myTag")
+
+	source := CustomTagSource{Foo: 42}
+	dest := CustomTagDest{}
+
+	d.Map(source, &dest, false)
+	assert.Equal(t, 42, dest.Renamed)
+}
+
+func TestSetTagNameInvalidatesCachedPlan(t *testing.T) {
+	d := NewDefaultMapper().(*defaultMapper)
+	source := CustomTagSource{Foo: 42}
+
+	// Before SetTagName, "myTag" is inert field tag text under the default
+	// "mapper" tag name, so resolution falls through to the plain Go field
+	// name and finds no match.
+	dest := CustomTagDest{}
+	d.Map(source, &dest, true)
+	assert.Equal(t, 0, dest.Renamed)
+
+	d.SetTagName("myTag")
+	dest = CustomTagDest{}
+	d.Map(source, &dest, true)
+	assert.Equal(t, 42, dest.Renamed)
+}
+
+type ConverterFieldSource struct {
+	Foo int
+}
+
+type ConverterFieldDest struct {
+	Foo string
+}
+
+func TestMapWithStructFieldCustomConverterUsesCachedPlanConverter(t *testing.T) {
+	RegisterMapping("int", "string", func(v any) (any, error) {
+		return fmt.Sprintf("%d", v.(int)), nil
+	})
+	defer UnregisterMapping("int", "string")
+
+	source := ConverterFieldSource{Foo: 7}
+	dest := ConverterFieldDest{}
+
+	Map(source, &dest, false)
+	assert.Equal(t, "7", dest.Foo)
+}
+
+func TestMapWithStructFieldConverterPlanRebuildsAfterUnregister(t *testing.T) {
+	d := NewDefaultMapper().(*defaultMapper)
+	d.RegisterMapping("int", "string", func(v any) (any, error) {
+		return fmt.Sprintf("%d", v.(int)), nil
+	})
+
+	source := ConverterFieldSource{Foo: 7}
+	dest := ConverterFieldDest{}
+	assert.NoError(t, d.MapE(source, &dest, false))
+	assert.Equal(t, "7", dest.Foo)
+
+	d.UnregisterMapping("int", "string")
+
+	err := d.MapE(source, &ConverterFieldDest{}, false)
+	assert.Error(t, err, "plan cache should have been invalidated, leaving no converter for the field")
+}